@@ -1,14 +1,20 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"io/ioutil"
 	"log"
 	"os"
+	"time"
 
+	"github.com/xpt-nl/itc/fiscal"
 	"github.com/xpt-nl/itc/report"
+	"github.com/xpt-nl/itc/report/batch"
+	"github.com/xpt-nl/itc/report/parse"
 )
 
 const (
@@ -18,6 +24,7 @@ const (
 	 getAccounts: Returns list of available accounts. No arguments.
 	 getVendors: Returns list of available vendor numbers. No arguments.
 	 getReport: Downloads a report. Arguments: Vendor Number, Report Type, Report Subtype, DateType, Date.
+	 downloadRange: Downloads every report between -from and -to into -dir. Arguments: Vendor Number, Report Type, Report Subtype, DateType, -from, -to.
 For more details, see Reporter guide: http://help.apple.com/itc/appsreporterguide/#/itcbe21ac7db`
 	financeHelp = `Finance commands include:
 	 getHelp: Returns this help message. No arguments.
@@ -25,6 +32,7 @@ For more details, see Reporter guide: http://help.apple.com/itc/appsreporterguid
 	 getAccounts: Returns list of available accounts. No arguments.
 	 getVendorsAndRegions: Returns list of available vendors, regions, and report types. No arguments.
 	 getReport: Downloads a report. Arguments: Vendor Number, Region Code, Report Type, Fiscal Year, Fiscal Period.
+	 downloadRange: Downloads every report between -from and -to into -dir. Arguments: Vendor Number, Region Code, Report Type, -from, -to.
 For more details, see Reporter guide in the Resources and Help section on iTunes Connect.`
 )
 
@@ -49,6 +57,14 @@ Robot mode is intended for an automated script that’s used regularly. Messages
 	regionCode   = flag.String("regionCode", "", "Two-character code of country of report to download. For a list of country codes by vendor number, use getVendorsAndRegions command.")
 	fiscalYear   = flag.Int("fiscalYear", 0, "Four-digit year of report to download. Year is specific to Apple’s [fiscal calendar](https://itunesconnect.apple.com/WebObjects/iTunesConnect.woa/wa/jumpTo?page=fiscalcalendar).")
 	fiscalPeriod = flag.Int("fiscalPeriod", 0, "This is the period in fiscal year for the report you’re downloading (1–12). The period is specific to Apple’s [fiscal calendar](https://itunesconnect.apple.com/WebObjects/iTunesConnect.woa/wa/jumpTo?page=fiscalcalendar).")
+
+	parseReport = flag.Bool("parse", false, "Decode the downloaded report and print it as JSON instead of writing the raw .gz file.")
+
+	// downloadRange
+	from        = flag.String("from", "", "Start of the range for downloadRange: YYYYMMDD for Sales, a fiscal period (for example 2024-P01) for Finance.")
+	to          = flag.String("to", "", "End of the range for downloadRange, inclusive.")
+	dir         = flag.String("dir", ".", "Directory downloadRange saves reports into.")
+	concurrency = flag.Int("concurrency", 4, "Number of concurrent downloads for downloadRange.")
 )
 
 func main() {
@@ -95,9 +111,23 @@ func financeCommand(ctx context.Context, cli *report.Client) {
 	case "getReport":
 		res, err := cli.GetFinanceReport(ctx, *account, *vendor, *regionCode, *reportType, *fiscalYear, *fiscalPeriod)
 		handleError(err)
+		if *parseReport {
+			printParsedFinanceReport(res)
+			return
+		}
 		fileName := fmt.Sprintf("FinanceReport_%s.gz", *date)
 		ioutil.WriteFile(fileName, res, 0644)
 		fmt.Printf("Finance report saved to %s", fileName)
+	case "downloadRange":
+		fromPeriod, err := fiscal.ParsePeriod(*from)
+		handleError(err)
+		toPeriod, err := fiscal.ParsePeriod(*to)
+		handleError(err)
+
+		dl := batch.Downloader{Client: cli, Dir: *dir, Concurrency: *concurrency}
+		results, err := dl.DownloadFinanceRange(ctx, *account, *vendor, *regionCode, *reportType, fromPeriod, toPeriod)
+		handleError(err)
+		printBatchResults(results)
 	default:
 		fmt.Print(financeHelp)
 	}
@@ -120,14 +150,59 @@ func salesCommand(ctx context.Context, cli *report.Client) {
 	case "getReport":
 		res, err := cli.GetSalesReport(ctx, *account, *vendor, *reportType, *reportSubType, *dateType, *date)
 		handleError(err)
+		if *parseReport {
+			printParsedSalesReport(res)
+			return
+		}
 		fileName := fmt.Sprintf("SalesReport_%s.gz", *date)
 		ioutil.WriteFile(fileName, res, 0644)
 		fmt.Printf("Report saved to %s", fileName)
+	case "downloadRange":
+		fromDate, err := time.Parse("20060102", *from)
+		handleError(err)
+		toDate, err := time.Parse("20060102", *to)
+		handleError(err)
+
+		dl := batch.Downloader{Client: cli, Dir: *dir, Concurrency: *concurrency}
+		results, err := dl.DownloadSalesRange(ctx, *account, *vendor, *reportType, *reportSubType, *dateType, fromDate, toDate)
+		handleError(err)
+		printBatchResults(results)
 	default:
 		fmt.Print(salesHelp)
 	}
 }
 
+func printParsedSalesReport(gz []byte) {
+	rows, err := parse.ParseSalesReport(bytes.NewReader(gz))
+	handleError(err)
+	b, err := json.Marshal(rows)
+	handleError(err)
+	fmt.Print(string(b))
+}
+
+func printParsedFinanceReport(gz []byte) {
+	fr, err := parse.ParseFinanceReport(bytes.NewReader(gz))
+	handleError(err)
+	b, err := json.Marshal(fr)
+	handleError(err)
+	fmt.Print(string(b))
+}
+
+func printBatchResults(results []batch.Result) {
+	for _, r := range results {
+		switch {
+		case r.Err != nil:
+			fmt.Printf("%s: error: %v\n", r.Token, r.Err)
+		case r.NotReady:
+			fmt.Printf("%s: not yet available\n", r.Token)
+		case r.Skipped:
+			fmt.Printf("%s: already downloaded at %s\n", r.Token, r.Path)
+		default:
+			fmt.Printf("%s: saved to %s\n", r.Token, r.Path)
+		}
+	}
+}
+
 func handleError(err error) {
 	if err != nil {
 		log.Print(err)
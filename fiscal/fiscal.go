@@ -99,11 +99,11 @@ func Quarter(year, quarter int) (start, end time.Time) {
 	return start.Add(qstart * Day), start.Add(qend * Day).Add(-time.Nanosecond)
 }
 
-// Period returns the start and end date of a fiscal period as used by Apple.
-// A period is either 35 or 28 days and so is roughly equivalent to a single
-// month. The year must be 2006 or higher. The period must be in the range
-// 1..12. Period returns start and end date of the period. End is the last
-// nanosecond before the start of the next period.
+// Period returns the start and end date of a fiscal period as used by
+// Apple. A period is either 35 or 28 days and so is roughly equivalent to a
+// single month. The year must be 2006 or higher. The period must be in the
+// range 1..12. Period returns start and end date of the period. End is the
+// last nanosecond before the start of the next period.
 func Period(year, period int) (start, end time.Time) {
 	start, end = Year(year)
 	if period < 1 {
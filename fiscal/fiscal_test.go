@@ -0,0 +1,58 @@
+package fiscal
+
+import "testing"
+
+func TestYearIsContiguous(t *testing.T) {
+	for y := 2006; y < 2040; y++ {
+		_, end := Year(y)
+		nextStart, _ := Year(y + 1)
+		if !end.Add(1).Equal(nextStart) {
+			t.Fatalf("Year(%d) end and Year(%d) start are not back to back: %v vs %v", y, y+1, end, nextStart)
+		}
+	}
+}
+
+// TestYear53WeekYears checks the fiscal years known to run 371 days (53
+// weeks) instead of the usual 364.
+func TestYear53WeekYears(t *testing.T) {
+	for _, y := range []int{2006, 2012, 2017, 2023, 2028, 2034, 2040} {
+		start, end := Year(y)
+		days := int(end.Sub(start).Hours() / 24)
+		if days != 371 {
+			t.Errorf("Year(%d) = %d days, want 371 (53-week year)", y, days)
+		}
+	}
+	for _, y := range []int{2007, 2013, 2024} {
+		start, end := Year(y)
+		days := int(end.Sub(start).Hours() / 24)
+		if days != 364 {
+			t.Errorf("Year(%d) = %d days, want 364", y, days)
+		}
+	}
+}
+
+func TestPeriodForDateRoundTrip(t *testing.T) {
+	for _, y := range []int{2022, 2023, 2024} { // 2023 is a 53-week year
+		start, end := Year(y)
+		for d := start; d.Before(end); d = d.Add(3 * Day) {
+			year, period := PeriodForDate(d)
+			pStart, pEnd := Period(year, period)
+			if d.Before(pStart) || d.After(pEnd) {
+				t.Fatalf("PeriodForDate(%v) = %d-P%d, but %v is outside [%v, %v]", d, year, period, d, pStart, pEnd)
+			}
+		}
+	}
+}
+
+func TestQuarterForDateRoundTrip(t *testing.T) {
+	for _, y := range []int{2022, 2023, 2024} {
+		start, end := Year(y)
+		for d := start; d.Before(end); d = d.Add(5 * Day) {
+			year, quarter := QuarterForDate(d)
+			qStart, qEnd := Quarter(year, quarter)
+			if d.Before(qStart) || d.After(qEnd) {
+				t.Fatalf("QuarterForDate(%v) = %d-Q%d, but %v is outside [%v, %v]", d, year, quarter, d, qStart, qEnd)
+			}
+		}
+	}
+}
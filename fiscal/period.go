@@ -0,0 +1,178 @@
+package fiscal
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// ErrBadPeriod is returned by ParsePeriod when its argument does not match
+// any of the accepted period formats, or names a period (1..12) or quarter
+// (1..4) outside its valid range. The year is not range-checked.
+var ErrBadPeriod = errors.New("fiscal: invalid period")
+
+var (
+	periodDashRe = regexp.MustCompile(`^(\d{4})-P(\d{1,2})$`)
+	periodRe     = regexp.MustCompile(`^(\d{4})P(\d{1,2})$`)
+	fyQuarterRe  = regexp.MustCompile(`^FY(\d{2})-Q(\d{1,2})$`)
+	quarterRe    = regexp.MustCompile(`^(\d{4})-Q(\d{1,2})$`)
+)
+
+// FiscalPeriod identifies a single fiscal period (roughly a month) within a
+// fiscal year, as used by Apple's Finance report API.
+type FiscalPeriod struct {
+	Year   int
+	Period int
+}
+
+// String formats p as e.g. "2024-P03".
+func (p FiscalPeriod) String() string {
+	return fmt.Sprintf("%04d-P%02d", p.Year, p.Period)
+}
+
+// ParsePeriod parses a fiscal period identifier. It accepts "2024-P03",
+// "2024P3", "FY24-Q2" and "2024-Q2"; the quarter forms resolve to the first
+// period of that quarter. It returns ErrBadPeriod if s does not match any
+// accepted format, or names a period outside the range 1..12.
+func ParsePeriod(s string) (FiscalPeriod, error) {
+	if m := periodDashRe.FindStringSubmatch(s); m != nil {
+		return newPeriod(m[1], m[2])
+	}
+	if m := periodRe.FindStringSubmatch(s); m != nil {
+		return newPeriod(m[1], m[2])
+	}
+	if m := fyQuarterRe.FindStringSubmatch(s); m != nil {
+		year, err := strconv.Atoi(m[1])
+		if err != nil {
+			return FiscalPeriod{}, fmt.Errorf("%w: %q", ErrBadPeriod, s)
+		}
+		return newQuarterPeriod(strconv.Itoa(2000+year), m[2])
+	}
+	if m := quarterRe.FindStringSubmatch(s); m != nil {
+		return newQuarterPeriod(m[1], m[2])
+	}
+	return FiscalPeriod{}, fmt.Errorf("%w: %q", ErrBadPeriod, s)
+}
+
+func newPeriod(yearStr, periodStr string) (FiscalPeriod, error) {
+	year, err1 := strconv.Atoi(yearStr)
+	period, err2 := strconv.Atoi(periodStr)
+	if err1 != nil || err2 != nil || period < 1 || period > 12 {
+		return FiscalPeriod{}, fmt.Errorf("%w: %q-%q", ErrBadPeriod, yearStr, periodStr)
+	}
+	return FiscalPeriod{Year: year, Period: period}, nil
+}
+
+func newQuarterPeriod(yearStr, quarterStr string) (FiscalPeriod, error) {
+	year, err1 := strconv.Atoi(yearStr)
+	quarter, err2 := strconv.Atoi(quarterStr)
+	if err1 != nil || err2 != nil || quarter < 1 || quarter > 4 {
+		return FiscalPeriod{}, fmt.Errorf("%w: %q-Q%q", ErrBadPeriod, yearStr, quarterStr)
+	}
+	return FiscalPeriod{Year: year, Period: (quarter-1)*3 + 1}, nil
+}
+
+// Next returns the fiscal period immediately following p, rolling over into
+// the next fiscal year after period 12.
+func (p FiscalPeriod) Next() FiscalPeriod {
+	if p.Period >= 12 {
+		return FiscalPeriod{Year: p.Year + 1, Period: 1}
+	}
+	return FiscalPeriod{Year: p.Year, Period: p.Period + 1}
+}
+
+// Prev returns the fiscal period immediately preceding p, rolling back into
+// the previous fiscal year before period 1.
+func (p FiscalPeriod) Prev() FiscalPeriod {
+	if p.Period <= 1 {
+		return FiscalPeriod{Year: p.Year - 1, Period: 12}
+	}
+	return FiscalPeriod{Year: p.Year, Period: p.Period - 1}
+}
+
+// Contains reports whether date falls within p.
+func (p FiscalPeriod) Contains(date time.Time) bool {
+	start, end := p.StartEnd()
+	return !date.Before(start) && !date.After(end)
+}
+
+// StartEnd returns the start and end date of p.
+func (p FiscalPeriod) StartEnd() (start, end time.Time) {
+	return Period(p.Year, p.Period)
+}
+
+// FiscalQuarter identifies a single fiscal quarter within a fiscal year.
+type FiscalQuarter struct {
+	Year    int
+	Quarter int
+}
+
+// Next returns the fiscal quarter immediately following q, rolling over into
+// the next fiscal year after quarter 4.
+func (q FiscalQuarter) Next() FiscalQuarter {
+	if q.Quarter >= 4 {
+		return FiscalQuarter{Year: q.Year + 1, Quarter: 1}
+	}
+	return FiscalQuarter{Year: q.Year, Quarter: q.Quarter + 1}
+}
+
+// StartEnd returns the start and end date of q.
+func (q FiscalQuarter) StartEnd() (start, end time.Time) {
+	return Quarter(q.Year, q.Quarter)
+}
+
+// PeriodsBetween enumerates every fiscal period overlapping [from, to],
+// inclusive.
+func PeriodsBetween(from, to time.Time) []FiscalPeriod {
+	if to.Before(from) {
+		return nil
+	}
+	year, period := PeriodForDate(from)
+	p := FiscalPeriod{Year: year, Period: period}
+
+	var periods []FiscalPeriod
+	for {
+		start, _ := p.StartEnd()
+		if start.After(to) {
+			break
+		}
+		periods = append(periods, p)
+		p = p.Next()
+	}
+	return periods
+}
+
+// QuartersBetween enumerates every fiscal quarter overlapping [from, to],
+// inclusive.
+func QuartersBetween(from, to time.Time) []FiscalQuarter {
+	if to.Before(from) {
+		return nil
+	}
+	year, quarter := QuarterForDate(from)
+	q := FiscalQuarter{Year: year, Quarter: quarter}
+
+	var quarters []FiscalQuarter
+	for {
+		start, _ := q.StartEnd()
+		if start.After(to) {
+			break
+		}
+		quarters = append(quarters, q)
+		q = q.Next()
+	}
+	return quarters
+}
+
+// CurrentPeriod returns the fiscal period containing now.
+func CurrentPeriod(now time.Time) FiscalPeriod {
+	year, period := PeriodForDate(now)
+	return FiscalPeriod{Year: year, Period: period}
+}
+
+// CurrentQuarter returns the fiscal quarter containing now.
+func CurrentQuarter(now time.Time) FiscalQuarter {
+	year, quarter := QuarterForDate(now)
+	return FiscalQuarter{Year: year, Quarter: quarter}
+}
@@ -0,0 +1,92 @@
+package fiscal
+
+import "testing"
+
+func TestParsePeriod(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    FiscalPeriod
+		wantErr bool
+	}{
+		{in: "2024-P03", want: FiscalPeriod{Year: 2024, Period: 3}},
+		{in: "2024P3", want: FiscalPeriod{Year: 2024, Period: 3}},
+		{in: "FY24-Q2", want: FiscalPeriod{Year: 2024, Period: 4}},
+		{in: "2024-Q2", want: FiscalPeriod{Year: 2024, Period: 4}},
+		{in: "2024-P13", wantErr: true},
+		{in: "2024-P00", wantErr: true},
+		{in: "not a period", wantErr: true},
+		{in: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParsePeriod(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParsePeriod(%q) = %v, want error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParsePeriod(%q) returned error: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParsePeriod(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestPeriodStringRoundTrip(t *testing.T) {
+	p := FiscalPeriod{Year: 2024, Period: 3}
+	got, err := ParsePeriod(p.String())
+	if err != nil {
+		t.Fatalf("ParsePeriod(%q): %v", p.String(), err)
+	}
+	if got != p {
+		t.Fatalf("ParsePeriod(p.String()) = %+v, want %+v", got, p)
+	}
+}
+
+func TestPeriodNextPrevRoundTrip(t *testing.T) {
+	periods := []FiscalPeriod{
+		{Year: 2023, Period: 1},
+		{Year: 2023, Period: 6},
+		{Year: 2023, Period: 12},
+		{Year: 2024, Period: 1},
+	}
+	for _, p := range periods {
+		if got := p.Next().Prev(); got != p {
+			t.Errorf("%+v.Next().Prev() = %+v, want %+v", p, got, p)
+		}
+		if got := p.Prev().Next(); got != p {
+			t.Errorf("%+v.Prev().Next() = %+v, want %+v", p, got, p)
+		}
+	}
+	if got, want := (FiscalPeriod{Year: 2023, Period: 12}).Next(), (FiscalPeriod{Year: 2024, Period: 1}); got != want {
+		t.Errorf("Period 12.Next() = %+v, want %+v (roll into next fiscal year)", got, want)
+	}
+	if got, want := (FiscalPeriod{Year: 2024, Period: 1}).Prev(), (FiscalPeriod{Year: 2023, Period: 12}); got != want {
+		t.Errorf("Period 1.Prev() = %+v, want %+v (roll into previous fiscal year)", got, want)
+	}
+}
+
+func TestQuarterNextRollover(t *testing.T) {
+	if got, want := (FiscalQuarter{Year: 2023, Quarter: 4}).Next(), (FiscalQuarter{Year: 2024, Quarter: 1}); got != want {
+		t.Errorf("Quarter 4.Next() = %+v, want %+v", got, want)
+	}
+}
+
+func TestPeriodsBetweenCoversWholeYear(t *testing.T) {
+	start, end := Year(2024)
+	periods := PeriodsBetween(start, end)
+	if len(periods) != 12 {
+		t.Fatalf("PeriodsBetween(full fiscal year) returned %d periods, want 12", len(periods))
+	}
+}
+
+func TestQuartersBetweenCoversWholeYear(t *testing.T) {
+	start, end := Year(2024)
+	quarters := QuartersBetween(start, end)
+	if len(quarters) != 4 {
+		t.Fatalf("QuartersBetween(full fiscal year) returned %d quarters, want 4", len(quarters))
+	}
+}
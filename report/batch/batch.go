@@ -0,0 +1,215 @@
+// Package batch walks a date or fiscal-period range and downloads every
+// Sales or Finance report it covers, resuming across runs. Transient
+// failures (rate limiting, 5xx responses, transport errors) are retried by
+// report.Client itself; batch only decides what to do with the final result
+// of a fetch.
+package batch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/xpt-nl/itc/fiscal"
+	"github.com/xpt-nl/itc/report"
+)
+
+// reportClient is the subset of *report.Client's methods Downloader needs.
+// Narrowing it to an interface lets tests inject a fake instead of talking
+// to a real Reporter/App Store Connect endpoint.
+type reportClient interface {
+	GetSalesReport(ctx context.Context, account, vendor int, reportType, reportSubType, dateType, date string) ([]byte, error)
+	GetFinanceReport(ctx context.Context, account, vendor int, regionCode, reportType string, fiscalYear, fiscalPeriod int) ([]byte, error)
+}
+
+// Downloader walks a date or fiscal-period range and downloads every report
+// it covers into Dir, skipping files that are already present.
+type Downloader struct {
+	Client      reportClient
+	Dir         string
+	Concurrency int
+}
+
+// Result describes the outcome of downloading a single report within a
+// range.
+type Result struct {
+	// Token identifies the report within the range: a date token (YYYYMMDD,
+	// YYYYMM or YYYY) for Sales reports, or a fiscal period (e.g.
+	// "2024-P03") for Finance reports.
+	Token string
+	Path  string
+	// Skipped is true when Path already existed on disk and the download
+	// was not attempted.
+	Skipped bool
+	// NotReady is true when Apple reported the file is not yet available
+	// for this period.
+	NotReady bool
+	Err      error
+}
+
+// DownloadSalesRange downloads every Sales (or Newsstand) report between
+// from and to, inclusive, enumerating the date tokens dateType requires
+// (daily/weekly/monthly/yearly).
+func (d Downloader) DownloadSalesRange(ctx context.Context, account, vendor int, reportType, reportSubType, dateType string, from, to time.Time) ([]Result, error) {
+	tokens, err := dateTokens(dateType, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.run(ctx, tokens, func(ctx context.Context, token string) ([]byte, error) {
+		return d.Client.GetSalesReport(ctx, account, vendor, reportType, reportSubType, dateType, token)
+	}, func(token string) string {
+		return filepath.Join(d.Dir, fmt.Sprintf("SalesReport_%s_%s_%s.gz", reportType, reportSubType, token))
+	}), nil
+}
+
+// DownloadFinanceRange downloads every Finance report between from and to,
+// inclusive, walking fiscal periods one at a time.
+func (d Downloader) DownloadFinanceRange(ctx context.Context, account, vendor int, region, reportType string, from, to fiscal.FiscalPeriod) ([]Result, error) {
+	periods, err := periodsBetween(from, to)
+	if err != nil {
+		return nil, err
+	}
+	tokens := make([]string, len(periods))
+	for i, p := range periods {
+		tokens[i] = p.String()
+	}
+
+	return d.run(ctx, tokens, func(ctx context.Context, token string) ([]byte, error) {
+		p, err := fiscal.ParsePeriod(token)
+		if err != nil {
+			return nil, err
+		}
+		return d.Client.GetFinanceReport(ctx, account, vendor, region, reportType, p.Year, p.Period)
+	}, func(token string) string {
+		return filepath.Join(d.Dir, fmt.Sprintf("FinanceReport_%s_%s.gz", region, token))
+	}), nil
+}
+
+// run fans fetch out across the configured Concurrency, bounded by a
+// semaphore, resuming files already on disk.
+func (d Downloader) run(ctx context.Context, tokens []string, fetch func(context.Context, string) ([]byte, error), pathFor func(string) string) []Result {
+	concurrency := d.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	results := make([]Result, len(tokens))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, token := range tokens {
+		i, token := i, token
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = d.fetchOne(ctx, token, pathFor(token), fetch)
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// fetchOne resolves a single token: skipping it if already on disk,
+// otherwise fetching it and writing it out. fetch (GetSalesReport or
+// GetFinanceReport) already retries rate-limited, 5xx and transport-level
+// errors internally via
+// report.Client.Retry, so fetchOne does not retry on top of that; it only
+// distinguishes ErrReportNotReady, which isn't worth retrying within a single
+// run, from every other error.
+func (d Downloader) fetchOne(ctx context.Context, token, path string, fetch func(context.Context, string) ([]byte, error)) Result {
+	res := Result{Token: token, Path: path}
+
+	if _, err := os.Stat(path); err == nil {
+		res.Skipped = true
+		return res
+	}
+
+	body, err := fetch(ctx, token)
+	if err != nil {
+		if errors.Is(err, report.ErrReportNotReady) {
+			res.NotReady = true
+			return res
+		}
+		res.Err = err
+		return res
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		res.Err = err
+		return res
+	}
+	if err := ioutil.WriteFile(path, body, 0644); err != nil {
+		res.Err = err
+		return res
+	}
+	return res
+}
+
+func dateTokens(dateType string, from, to time.Time) ([]string, error) {
+	if to.Before(from) {
+		return nil, errors.New("batch: range end before start")
+	}
+
+	var tokens []string
+	switch dateType {
+	case "Daily":
+		for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+			tokens = append(tokens, d.Format("20060102"))
+		}
+	case "Weekly":
+		for d := mondayOf(from); !d.After(to); d = d.AddDate(0, 0, 7) {
+			tokens = append(tokens, d.Format("20060102"))
+		}
+	case "Monthly":
+		start := time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, from.Location())
+		for d := start; !d.After(to); d = d.AddDate(0, 1, 0) {
+			tokens = append(tokens, d.Format("200601"))
+		}
+	case "Yearly":
+		for y := from.Year(); y <= to.Year(); y++ {
+			tokens = append(tokens, strconv.Itoa(y))
+		}
+	default:
+		return nil, fmt.Errorf("batch: unsupported dateType %q", dateType)
+	}
+	return tokens, nil
+}
+
+// mondayOf returns the Monday of the week containing t, as the Reporter
+// Weekly report is anchored to Mondays.
+func mondayOf(t time.Time) time.Time {
+	weekday := int(t.Weekday())
+	if weekday == 0 {
+		weekday = 7
+	}
+	return t.AddDate(0, 0, -(weekday - 1))
+}
+
+// periodsBetween enumerates every fiscal period from from to to, inclusive,
+// mirroring dateTokens' range validation.
+func periodsBetween(from, to fiscal.FiscalPeriod) ([]fiscal.FiscalPeriod, error) {
+	if periodBefore(to, from) {
+		return nil, errors.New("batch: range end before start")
+	}
+	var periods []fiscal.FiscalPeriod
+	for p := from; !periodBefore(to, p); p = p.Next() {
+		periods = append(periods, p)
+	}
+	return periods, nil
+}
+
+func periodBefore(a, b fiscal.FiscalPeriod) bool {
+	if a.Year != b.Year {
+		return a.Year < b.Year
+	}
+	return a.Period < b.Period
+}
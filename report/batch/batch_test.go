@@ -0,0 +1,386 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/xpt-nl/itc/fiscal"
+	"github.com/xpt-nl/itc/report"
+)
+
+func TestFetchOneSkipsExistingFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "report.gz")
+	if err := ioutil.WriteFile(path, []byte("cached"), 0644); err != nil {
+		t.Fatalf("seeding existing file: %v", err)
+	}
+
+	var d Downloader
+	called := false
+	res := d.fetchOne(context.Background(), "tok", path, func(context.Context, string) ([]byte, error) {
+		called = true
+		return nil, errors.New("should not be called")
+	})
+
+	if !res.Skipped {
+		t.Errorf("fetchOne on an existing file: Skipped = false, want true")
+	}
+	if called {
+		t.Errorf("fetchOne called fetch for a file that already exists on disk")
+	}
+}
+
+func TestFetchOneNotReady(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "report.gz")
+
+	var d Downloader
+	res := d.fetchOne(context.Background(), "tok", path, func(context.Context, string) ([]byte, error) {
+		return nil, report.ErrReportNotReady
+	})
+
+	if !res.NotReady {
+		t.Errorf("fetchOne on ErrReportNotReady: NotReady = false, want true")
+	}
+	if res.Err != nil {
+		t.Errorf("fetchOne on ErrReportNotReady: Err = %v, want nil", res.Err)
+	}
+}
+
+// TestFetchOneDoesNotRetry locks in the chunk0-5 fix: report.Client already
+// retries 429/503 responses internally, so fetchOne must call fetch exactly
+// once and surface whatever error comes back, rather than retrying on top of
+// that.
+func TestFetchOneDoesNotRetry(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "report.gz")
+
+	var calls int32
+	wantErr := errors.New("boom")
+	var d Downloader
+	res := d.fetchOne(context.Background(), "tok", path, func(context.Context, string) ([]byte, error) {
+		atomic.AddInt32(&calls, 1)
+		return nil, wantErr
+	})
+
+	if calls != 1 {
+		t.Errorf("fetchOne called fetch %d times, want exactly 1 (no batch-level retry)", calls)
+	}
+	if !errors.Is(res.Err, wantErr) {
+		t.Errorf("fetchOne: Err = %v, want %v", res.Err, wantErr)
+	}
+}
+
+func TestFetchOneWritesReport(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "nested", "report.gz")
+
+	var d Downloader
+	res := d.fetchOne(context.Background(), "tok", path, func(context.Context, string) ([]byte, error) {
+		return []byte("report body"), nil
+	})
+
+	if res.Err != nil {
+		t.Fatalf("fetchOne: %v", res.Err)
+	}
+	body, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading written report: %v", err)
+	}
+	if string(body) != "report body" {
+		t.Errorf("written report body = %q, want %q", body, "report body")
+	}
+}
+
+func TestRunRespectsConcurrency(t *testing.T) {
+	dir := t.TempDir()
+	d := Downloader{Dir: dir, Concurrency: 3}
+
+	tokens := make([]string, 10)
+	for i := range tokens {
+		tokens[i] = string(rune('a' + i))
+	}
+
+	var inFlight, maxInFlight int32
+	results := d.run(context.Background(), tokens, func(ctx context.Context, token string) ([]byte, error) {
+		n := atomic.AddInt32(&inFlight, 1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+				break
+			}
+		}
+		atomic.AddInt32(&inFlight, -1)
+		return []byte("x"), nil
+	}, func(token string) string {
+		return filepath.Join(dir, token)
+	})
+
+	if len(results) != len(tokens) {
+		t.Fatalf("run returned %d results, want %d", len(results), len(tokens))
+	}
+	for i, res := range results {
+		if res.Err != nil {
+			t.Errorf("result %d: %v", i, res.Err)
+		}
+	}
+	if maxInFlight > int32(d.Concurrency) {
+		t.Errorf("observed %d concurrent fetches, want <= %d", maxInFlight, d.Concurrency)
+	}
+}
+
+func date(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestDateTokens(t *testing.T) {
+	tests := []struct {
+		name     string
+		dateType string
+		from, to time.Time
+		want     []string
+		wantErr  bool
+	}{
+		{
+			name:     "daily across a month boundary",
+			dateType: "Daily",
+			from:     date(2024, 1, 30),
+			to:       date(2024, 2, 2),
+			want:     []string{"20240130", "20240131", "20240201", "20240202"},
+		},
+		{
+			name:     "weekly anchors to the preceding Monday",
+			dateType: "Weekly",
+			from:     date(2024, 1, 3), // a Wednesday
+			to:       date(2024, 1, 3),
+			want:     []string{"20240101"}, // the Monday of that week
+		},
+		{
+			name:     "weekly across a year boundary",
+			dateType: "Weekly",
+			from:     date(2023, 12, 28), // Thursday of the last week of 2023
+			to:       date(2024, 1, 5),
+			want:     []string{"20231225", "20240101"},
+		},
+		{
+			name:     "monthly across a year boundary",
+			dateType: "Monthly",
+			from:     date(2023, 12, 15),
+			to:       date(2024, 1, 15),
+			want:     []string{"202312", "202401"},
+		},
+		{
+			name:     "yearly across multiple years",
+			dateType: "Yearly",
+			from:     date(2022, 6, 1),
+			to:       date(2024, 3, 1),
+			want:     []string{"2022", "2023", "2024"},
+		},
+		{
+			name:     "range end before start",
+			dateType: "Daily",
+			from:     date(2024, 2, 1),
+			to:       date(2024, 1, 1),
+			wantErr:  true,
+		},
+		{
+			name:     "unsupported dateType",
+			dateType: "Hourly",
+			from:     date(2024, 1, 1),
+			to:       date(2024, 1, 1),
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := dateTokens(tt.dateType, tt.from, tt.to)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("dateTokens(%q) = %v, want error", tt.dateType, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("dateTokens(%q): %v", tt.dateType, err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("dateTokens(%q) = %v, want %v", tt.dateType, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("dateTokens(%q)[%d] = %q, want %q", tt.dateType, i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestPeriodsBetween(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to fiscal.FiscalPeriod
+		want     []fiscal.FiscalPeriod
+		wantErr  bool
+	}{
+		{
+			name: "single fiscal year",
+			from: fiscal.FiscalPeriod{Year: 2024, Period: 1},
+			to:   fiscal.FiscalPeriod{Year: 2024, Period: 3},
+			want: []fiscal.FiscalPeriod{
+				{Year: 2024, Period: 1},
+				{Year: 2024, Period: 2},
+				{Year: 2024, Period: 3},
+			},
+		},
+		{
+			// 2023 is a 53-week fiscal year (only period 3's length changes,
+			// not the period count), so the rollover from its last period
+			// into the next fiscal year's first period works the same as
+			// any other year boundary.
+			name: "rolls over a 53-week fiscal year boundary",
+			from: fiscal.FiscalPeriod{Year: 2023, Period: 12},
+			to:   fiscal.FiscalPeriod{Year: 2024, Period: 1},
+			want: []fiscal.FiscalPeriod{
+				{Year: 2023, Period: 12},
+				{Year: 2024, Period: 1},
+			},
+		},
+		{
+			name:    "range end before start",
+			from:    fiscal.FiscalPeriod{Year: 2024, Period: 5},
+			to:      fiscal.FiscalPeriod{Year: 2024, Period: 3},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := periodsBetween(tt.from, tt.to)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("periodsBetween(%v, %v) = %v, want error", tt.from, tt.to, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("periodsBetween(%v, %v): %v", tt.from, tt.to, err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("periodsBetween(%v, %v) = %v, want %v", tt.from, tt.to, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("periodsBetween(%v, %v)[%d] = %v, want %v", tt.from, tt.to, i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+// fakeReportClient is a reportClient that records the calls it receives and
+// returns canned responses, so DownloadSalesRange/DownloadFinanceRange can be
+// driven end-to-end without a real Reporter/App Store Connect endpoint.
+type fakeReportClient struct {
+	mu          sync.Mutex
+	salesDates  []string
+	financeArgs [][2]int // [fiscalYear, fiscalPeriod]
+}
+
+func (f *fakeReportClient) GetSalesReport(ctx context.Context, account, vendor int, reportType, reportSubType, dateType, date string) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.salesDates = append(f.salesDates, date)
+	return []byte("sales-" + date), nil
+}
+
+func (f *fakeReportClient) GetFinanceReport(ctx context.Context, account, vendor int, regionCode, reportType string, fiscalYear, fiscalPeriod int) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.financeArgs = append(f.financeArgs, [2]int{fiscalYear, fiscalPeriod})
+	return []byte("finance"), nil
+}
+
+func TestDownloadSalesRangeEndToEnd(t *testing.T) {
+	dir := t.TempDir()
+	fake := &fakeReportClient{}
+	d := Downloader{Client: fake, Dir: dir}
+
+	results, err := d.DownloadSalesRange(context.Background(), 1, 2, "Sales", "Summary", "Daily", date(2024, 1, 1), date(2024, 1, 3))
+	if err != nil {
+		t.Fatalf("DownloadSalesRange: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("DownloadSalesRange returned %d results, want 3", len(results))
+	}
+	for _, res := range results {
+		if res.Err != nil {
+			t.Errorf("result %q: %v", res.Token, res.Err)
+		}
+		if _, err := ioutil.ReadFile(res.Path); err != nil {
+			t.Errorf("result %q: report not written to disk: %v", res.Token, err)
+		}
+	}
+	wantDates := []string{"20240101", "20240102", "20240103"}
+	gotDates := append([]string{}, fake.salesDates...)
+	sort.Strings(gotDates)
+	for i, want := range wantDates {
+		if i >= len(gotDates) || gotDates[i] != want {
+			t.Errorf("GetSalesReport dates = %v, want %v", gotDates, wantDates)
+			break
+		}
+	}
+}
+
+func TestDownloadFinanceRangeEndToEnd(t *testing.T) {
+	dir := t.TempDir()
+	fake := &fakeReportClient{}
+	d := Downloader{Client: fake, Dir: dir}
+
+	results, err := d.DownloadFinanceRange(context.Background(), 1, 2, "US", "Financial", fiscal.FiscalPeriod{Year: 2024, Period: 1}, fiscal.FiscalPeriod{Year: 2024, Period: 2})
+	if err != nil {
+		t.Fatalf("DownloadFinanceRange: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("DownloadFinanceRange returned %d results, want 2", len(results))
+	}
+	for _, res := range results {
+		if res.Err != nil {
+			t.Errorf("result %q: %v", res.Token, res.Err)
+		}
+	}
+	want := [][2]int{{2024, 1}, {2024, 2}}
+	got := append([][2]int{}, fake.financeArgs...)
+	sort.Slice(got, func(i, j int) bool {
+		if got[i][0] != got[j][0] {
+			return got[i][0] < got[j][0]
+		}
+		return got[i][1] < got[j][1]
+	})
+	if len(got) != len(want) {
+		t.Fatalf("GetFinanceReport args = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("GetFinanceReport args = %v, want %v", got, want)
+			break
+		}
+	}
+}
+
+// TestDownloadFinanceRangeRejectsInvertedRange locks in the fix matching
+// DownloadSalesRange/dateTokens: an inverted period range must fail loudly
+// rather than silently returning zero results.
+func TestDownloadFinanceRangeRejectsInvertedRange(t *testing.T) {
+	d := Downloader{Client: &fakeReportClient{}, Dir: t.TempDir()}
+
+	_, err := d.DownloadFinanceRange(context.Background(), 1, 2, "US", "Financial", fiscal.FiscalPeriod{Year: 2024, Period: 5}, fiscal.FiscalPeriod{Year: 2024, Period: 3})
+	if err == nil {
+		t.Fatal("DownloadFinanceRange with to before from returned no error")
+	}
+}
@@ -0,0 +1,187 @@
+package report
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Sentinel errors that an APIError can be compared against with errors.Is,
+// so callers can branch on the kind of failure without inspecting its
+// message text.
+var (
+	ErrReportNotReady     = errors.New("report: report not yet available")
+	ErrInvalidCredentials = errors.New("report: invalid credentials")
+	ErrRateLimited        = errors.New("report: rate limited")
+)
+
+// APIError is returned by every Get* method when the Reporter or App Store
+// Connect endpoint responds with anything other than 200 OK.
+type APIError struct {
+	StatusCode int
+	Code       string
+	Message    string
+	Raw        []byte
+
+	sentinel error
+}
+
+func (e *APIError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("report: %d %s: %s", e.StatusCode, e.Code, e.Message)
+	}
+	return fmt.Sprintf("report: %d: %s", e.StatusCode, strings.TrimSpace(string(e.Raw)))
+}
+
+// Unwrap lets errors.Is(err, ErrReportNotReady) (and friends) see past the
+// APIError to the sentinel it was classified as, if any.
+func (e *APIError) Unwrap() error {
+	return e.sentinel
+}
+
+// reporterXMLError is the body the legacy Reporter endpoint returns in
+// Robot.xml mode: <Error><Code>...</Code><Message>...</Message></Error>.
+type reporterXMLError struct {
+	XMLName xml.Name `xml:"Error"`
+	Code    string   `xml:"Code"`
+	Message string   `xml:"Message"`
+}
+
+// decodeReporterError builds an APIError from a non-200 legacy Reporter
+// response, parsing its XML body when the Client is in Robot.xml mode.
+func decodeReporterError(statusCode int, body []byte, mode string) *APIError {
+	apiErr := &APIError{StatusCode: statusCode, Raw: body}
+
+	if mode == "Robot.xml" {
+		var xe reporterXMLError
+		if err := xml.Unmarshal(body, &xe); err == nil {
+			apiErr.Code = xe.Code
+			apiErr.Message = xe.Message
+		}
+	}
+	if apiErr.Message == "" {
+		apiErr.Message = strings.TrimSpace(string(body))
+	}
+	apiErr.sentinel = classifySentinel(statusCode, apiErr.Code, apiErr.Message)
+	return apiErr
+}
+
+// ascErrorBody is the body the App Store Connect API v1 returns on failure:
+// a JSON "errors" array of {status, code, title, detail}.
+type ascErrorBody struct {
+	Errors []struct {
+		Status string `json:"status"`
+		Code   string `json:"code"`
+		Title  string `json:"title"`
+		Detail string `json:"detail"`
+	} `json:"errors"`
+}
+
+// decodeASCError builds an APIError from a non-200 App Store Connect API v1
+// response.
+func decodeASCError(statusCode int, body []byte) *APIError {
+	apiErr := &APIError{StatusCode: statusCode, Raw: body}
+
+	var parsed ascErrorBody
+	if err := json.Unmarshal(body, &parsed); err == nil && len(parsed.Errors) > 0 {
+		apiErr.Code = parsed.Errors[0].Code
+		apiErr.Message = parsed.Errors[0].Detail
+		if apiErr.Message == "" {
+			apiErr.Message = parsed.Errors[0].Title
+		}
+	}
+	if apiErr.Message == "" {
+		apiErr.Message = strings.TrimSpace(string(body))
+	}
+	apiErr.sentinel = classifySentinel(statusCode, apiErr.Code, apiErr.Message)
+	return apiErr
+}
+
+func classifySentinel(statusCode int, code, message string) error {
+	switch {
+	case statusCode == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden:
+		return ErrInvalidCredentials
+	case strings.Contains(strings.ToLower(code), "not_ready"),
+		strings.Contains(strings.ToLower(message), "not yet available"),
+		strings.Contains(strings.ToLower(message), "no report available"):
+		return ErrReportNotReady
+	default:
+		return nil
+	}
+}
+
+// retryableStatus reports whether a response with this status code is worth
+// retrying: a rate limit, or any 5xx, since those are almost always
+// transient (the server overloaded, a gateway timing out, ...).
+func retryableStatus(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || statusCode >= 500
+}
+
+// RetryPolicy controls how Client retries a request on a rate-limited, 5xx,
+// or transport-level error.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+var defaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 4,
+	BaseDelay:   time.Second,
+	MaxDelay:    30 * time.Second,
+}
+
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = defaultRetryPolicy.MaxAttempts
+	}
+	if p.BaseDelay <= 0 {
+		p.BaseDelay = defaultRetryPolicy.BaseDelay
+	}
+	if p.MaxDelay <= 0 {
+		p.MaxDelay = defaultRetryPolicy.MaxDelay
+	}
+	return p
+}
+
+// backoff returns a jittered exponential delay for the given retry attempt
+// (0-based).
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.BaseDelay * time.Duration(1<<uint(attempt))
+	if d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	return time.Duration(rand.Int63n(int64(d) + 1))
+}
+
+// sleepRetry waits before the next retry attempt, honoring a Retry-After
+// header when the server sent one, and returns false if ctx was canceled
+// first.
+func sleepRetry(ctx context.Context, retry RetryPolicy, attempt int, retryAfter string) bool {
+	delay := retry.backoff(attempt)
+	if retryAfter != "" {
+		if secs, err := strconv.Atoi(retryAfter); err == nil {
+			delay = time.Duration(secs) * time.Second
+		} else if t, err := http.ParseTime(retryAfter); err == nil {
+			delay = time.Until(t)
+		}
+	}
+	if delay < 0 {
+		delay = 0
+	}
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(delay):
+		return true
+	}
+}
@@ -0,0 +1,150 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestDecodeReporterErrorXML(t *testing.T) {
+	body := []byte(`<Error><Code>2034</Code><Message>No report available.</Message></Error>`)
+	apiErr := decodeReporterError(http.StatusInternalServerError, body, "Robot.xml")
+
+	if apiErr.Code != "2034" {
+		t.Errorf("Code = %q, want %q", apiErr.Code, "2034")
+	}
+	if apiErr.Message != "No report available." {
+		t.Errorf("Message = %q, want %q", apiErr.Message, "No report available.")
+	}
+	if !errors.Is(apiErr, ErrReportNotReady) {
+		t.Errorf("errors.Is(apiErr, ErrReportNotReady) = false, want true")
+	}
+}
+
+func TestDecodeReporterErrorNormalMode(t *testing.T) {
+	body := []byte("plain text failure")
+	apiErr := decodeReporterError(http.StatusUnauthorized, body, "Normal")
+
+	if apiErr.Code != "" {
+		t.Errorf("Code = %q, want empty (Normal mode doesn't parse XML)", apiErr.Code)
+	}
+	if apiErr.Message != "plain text failure" {
+		t.Errorf("Message = %q, want raw body", apiErr.Message)
+	}
+	if !errors.Is(apiErr, ErrInvalidCredentials) {
+		t.Errorf("errors.Is(apiErr, ErrInvalidCredentials) = false, want true")
+	}
+}
+
+func TestDecodeASCError(t *testing.T) {
+	body := []byte(`{"errors":[{"status":"429","code":"RATE_LIMIT_EXCEEDED","title":"Too many requests","detail":""}]}`)
+	apiErr := decodeASCError(http.StatusTooManyRequests, body)
+
+	if apiErr.Code != "RATE_LIMIT_EXCEEDED" {
+		t.Errorf("Code = %q, want %q", apiErr.Code, "RATE_LIMIT_EXCEEDED")
+	}
+	if apiErr.Message != "Too many requests" {
+		t.Errorf("Message = %q, want title fallback %q", apiErr.Message, "Too many requests")
+	}
+	if !errors.Is(apiErr, ErrRateLimited) {
+		t.Errorf("errors.Is(apiErr, ErrRateLimited) = false, want true")
+	}
+}
+
+func TestDecodeASCErrorUnparseableBody(t *testing.T) {
+	apiErr := decodeASCError(http.StatusServiceUnavailable, []byte("not json"))
+	if apiErr.Message != "not json" {
+		t.Errorf("Message = %q, want raw body fallback", apiErr.Message)
+	}
+	if errors.Is(apiErr, ErrReportNotReady) || errors.Is(apiErr, ErrRateLimited) || errors.Is(apiErr, ErrInvalidCredentials) {
+		t.Errorf("apiErr unexpectedly classified as a known sentinel")
+	}
+}
+
+func TestClassifySentinel(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		code       string
+		message    string
+		want       error
+	}{
+		{"rate limited", http.StatusTooManyRequests, "", "", ErrRateLimited},
+		{"unauthorized", http.StatusUnauthorized, "", "", ErrInvalidCredentials},
+		{"forbidden", http.StatusForbidden, "", "", ErrInvalidCredentials},
+		{"not ready by code", http.StatusInternalServerError, "NOT_READY", "", ErrReportNotReady},
+		{"not ready by message", http.StatusInternalServerError, "", "Report not yet available", ErrReportNotReady},
+		{"no report available message", http.StatusInternalServerError, "", "No report available for this period", ErrReportNotReady},
+		{"unclassified", http.StatusBadRequest, "BAD_REQUEST", "malformed filter", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := classifySentinel(tt.statusCode, tt.code, tt.message)
+			if got != tt.want {
+				t.Errorf("classifySentinel(%d, %q, %q) = %v, want %v", tt.statusCode, tt.code, tt.message, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryableStatus(t *testing.T) {
+	retryable := []int{http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout}
+	for _, sc := range retryable {
+		if !retryableStatus(sc) {
+			t.Errorf("retryableStatus(%d) = false, want true", sc)
+		}
+	}
+	notRetryable := []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound}
+	for _, sc := range notRetryable {
+		if retryableStatus(sc) {
+			t.Errorf("retryableStatus(%d) = true, want false", sc)
+		}
+	}
+}
+
+func TestRetryPolicyBackoffCapsAtMaxDelay(t *testing.T) {
+	p := RetryPolicy{MaxAttempts: 4, BaseDelay: time.Second, MaxDelay: 5 * time.Second}
+	for attempt := 0; attempt < 10; attempt++ {
+		if d := p.backoff(attempt); d > p.MaxDelay {
+			t.Errorf("backoff(%d) = %v, want <= MaxDelay %v", attempt, d, p.MaxDelay)
+		}
+	}
+}
+
+func TestRetryPolicyWithDefaults(t *testing.T) {
+	got := RetryPolicy{}.withDefaults()
+	if got != defaultRetryPolicy {
+		t.Errorf("RetryPolicy{}.withDefaults() = %+v, want %+v", got, defaultRetryPolicy)
+	}
+
+	custom := RetryPolicy{MaxAttempts: 7}.withDefaults()
+	if custom.MaxAttempts != 7 {
+		t.Errorf("withDefaults() overrode an explicit MaxAttempts: got %d, want 7", custom.MaxAttempts)
+	}
+	if custom.BaseDelay != defaultRetryPolicy.BaseDelay {
+		t.Errorf("withDefaults() BaseDelay = %v, want default %v", custom.BaseDelay, defaultRetryPolicy.BaseDelay)
+	}
+}
+
+func TestSleepRetryHonorsRetryAfterSeconds(t *testing.T) {
+	start := time.Now()
+	ok := sleepRetry(context.Background(), RetryPolicy{MaxAttempts: 4, BaseDelay: time.Hour, MaxDelay: time.Hour}, 0, "0")
+	if !ok {
+		t.Fatal("sleepRetry returned false")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("sleepRetry with Retry-After: 0 took %v, want near-instant", elapsed)
+	}
+}
+
+func TestSleepRetryCanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	ok := sleepRetry(ctx, RetryPolicy{MaxAttempts: 4, BaseDelay: time.Hour, MaxDelay: time.Hour}, 0, "")
+	if ok {
+		t.Error("sleepRetry on a canceled context returned true, want false")
+	}
+}
@@ -0,0 +1,313 @@
+package report
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ascSalesEndpoint = "https://api.appstoreconnect.apple.com/v1/salesReports"
+var ascFinanceEndpoint = "https://api.appstoreconnect.apple.com/v1/financeReports"
+
+const (
+	jwtExpiry   = 20 * time.Minute
+	jwtAudience = "appstoreconnect-v1"
+)
+
+// JWTConfig holds the credentials needed to authenticate against the App
+// Store Connect API v1 with a signed JWT, the transport Apple recommends for
+// new integrations instead of the legacy Reporter access token. See
+// https://developer.apple.com/documentation/appstoreconnectapi/generating_tokens_for_api_requests
+type JWTConfig struct {
+	IssuerID     string
+	KeyID        string
+	VendorNumber int
+
+	// PrivateKeyPath is the path to the ES256 .p8 private key downloaded from
+	// App Store Connect. Ignored when PrivateKey is set.
+	PrivateKeyPath string
+	// PrivateKey is the PEM-encoded ES256 private key. Takes precedence over
+	// PrivateKeyPath.
+	PrivateKey []byte
+
+	// Retry controls how the Client retries a request that came back
+	// rate-limited or temporarily unavailable. The zero value uses sane
+	// defaults.
+	Retry RetryPolicy
+}
+
+// Check validates a JWTConfig.
+func (cfg JWTConfig) Check() error {
+	if cfg.IssuerID == "" {
+		return errors.New("issuer id not set")
+	}
+	if cfg.KeyID == "" {
+		return errors.New("key id not set")
+	}
+	if cfg.VendorNumber <= 0 {
+		return errors.New("wrong vendor number")
+	}
+	if cfg.PrivateKeyPath == "" && len(cfg.PrivateKey) == 0 {
+		return errors.New("private key not set")
+	}
+	return nil
+}
+
+// jwtAuth holds the state needed to mint and cache App Store Connect bearer
+// tokens. It is held behind a pointer on Client so that value-receiver
+// methods still share and refresh a single cached token.
+type jwtAuth struct {
+	issuerID     string
+	keyID        string
+	vendorNumber int
+	privateKey   *ecdsa.PrivateKey
+
+	mu          sync.Mutex
+	token       string
+	tokenExpiry time.Time
+}
+
+// NewJWTClient yields a new Client that authenticates against the App Store
+// Connect API v1 with a signed JWT instead of the legacy Reporter access
+// token. GetSalesReport and GetFinanceReport keep their existing signatures
+// but are routed through the modern REST endpoints.
+func NewJWTClient(cfg JWTConfig) (*Client, error) {
+	err := cfg.Check()
+	if err != nil {
+		return nil, err
+	}
+
+	keyBytes := cfg.PrivateKey
+	if len(keyBytes) == 0 {
+		keyBytes, err = ioutil.ReadFile(cfg.PrivateKeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("reading private key: %w", err)
+		}
+	}
+	key, err := parseES256PrivateKey(keyBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{
+		httpCli: newHTTPClient(),
+		jwt: &jwtAuth{
+			issuerID:     cfg.IssuerID,
+			keyID:        cfg.KeyID,
+			vendorNumber: cfg.VendorNumber,
+			privateKey:   key,
+		},
+		Retry: cfg.Retry,
+	}, nil
+}
+
+func parseES256PrivateKey(pemBytes []byte) (*ecdsa.PrivateKey, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, errors.New("invalid private key: not PEM encoded")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing private key: %w", err)
+	}
+	ecKey, ok := key.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("invalid private key: not an ES256 (ECDSA P-256) key")
+	}
+	if ecKey.Curve != elliptic.P256() {
+		return nil, errors.New("invalid private key: not an ES256 (ECDSA P-256) key")
+	}
+	return ecKey, nil
+}
+
+// bearerToken returns a cached JWT if it still has life left, minting a
+// fresh one otherwise. It is safe for concurrent use: batch.Downloader fans
+// requests for the same Client out across goroutines, and they all share
+// this jwtAuth.
+func (a *jwtAuth) bearerToken() (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.token != "" && time.Now().Before(a.tokenExpiry) {
+		return a.token, nil
+	}
+	token, expiry, err := mintJWT(a.issuerID, a.keyID, a.privateKey)
+	if err != nil {
+		return "", err
+	}
+	a.token = token
+	a.tokenExpiry = expiry
+	return token, nil
+}
+
+// mintJWT builds and signs an ES256 JWT of the shape App Store Connect
+// expects, valid for jwtExpiry.
+func mintJWT(issuerID, keyID string, key *ecdsa.PrivateKey) (token string, expiry time.Time, err error) {
+	now := time.Now()
+	expiry = now.Add(jwtExpiry)
+
+	header := map[string]string{
+		"alg": "ES256",
+		"kid": keyID,
+		"typ": "JWT",
+	}
+	claims := map[string]interface{}{
+		"iss": issuerID,
+		"iat": now.Unix(),
+		"exp": expiry.Unix(),
+		"aud": jwtAudience,
+	}
+
+	headerJSON, err := json.Marshal(header)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	claimsJSON, err := json.Marshal(claims)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	signingInput := base64URLEncode(headerJSON) + "." + base64URLEncode(claimsJSON)
+
+	hash := sha256.Sum256([]byte(signingInput))
+	r, s, err := ecdsa.Sign(rand.Reader, key, hash[:])
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("signing jwt: %w", err)
+	}
+
+	return signingInput + "." + base64URLEncode(encodeES256Signature(r, s)), expiry, nil
+}
+
+func base64URLEncode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// encodeES256Signature packs r and s into the fixed-width big-endian pair a
+// JWS ES256 signature requires (32 bytes each for the P-256 curve).
+func encodeES256Signature(r, s *big.Int) []byte {
+	const size = 32
+	sig := make([]byte, 2*size)
+	r.FillBytes(sig[:size])
+	s.FillBytes(sig[size:])
+	return sig
+}
+
+// ascSalesReportTypes are the report types the App Store Connect API v1
+// exposes beyond the legacy Reporter's Sales/Newsstand.
+var ascSalesReportTypes = map[string]bool{
+	"SUBSCRIPTION":       true,
+	"SUBSCRIBER":         true,
+	"SUBSCRIPTION_EVENT": true,
+	"PRE_ORDER":          true,
+	"NEWSSTAND":          true,
+}
+
+func isASCSalesReportType(reportType string) bool {
+	return ascSalesReportTypes[reportType]
+}
+
+// ascSalesReport fetches a sales report from the App Store Connect API v1.
+func (c Client) ascSalesReport(ctx context.Context, vendor int, reportType, reportSubType, dateType, date string) ([]byte, error) {
+	q := url.Values{}
+	q.Set("filter[reportType]", strings.ToUpper(reportType))
+	q.Set("filter[reportSubType]", strings.ToUpper(reportSubType))
+	q.Set("filter[frequency]", ascFrequency(dateType))
+	q.Set("filter[reportDate]", date)
+	q.Set("filter[vendorNumber]", strconv.Itoa(vendor))
+
+	return c.ascRequest(ctx, ascSalesEndpoint, q)
+}
+
+// ascFinanceReport fetches a finance report from the App Store Connect API
+// v1.
+func (c Client) ascFinanceReport(ctx context.Context, vendor int, regionCode, reportType string, fiscalYear, fiscalPeriod int) ([]byte, error) {
+	q := url.Values{}
+	q.Set("filter[regionCode]", regionCode)
+	q.Set("filter[reportType]", strings.ToUpper(reportType))
+	q.Set("filter[reportDate]", fmt.Sprintf("%04d-%02d", fiscalYear, fiscalPeriod))
+	q.Set("filter[vendorNumber]", strconv.Itoa(vendor))
+
+	return c.ascRequest(ctx, ascFinanceEndpoint, q)
+}
+
+func ascFrequency(dateType string) string {
+	switch dateType {
+	case "Daily":
+		return "DAILY"
+	case "Weekly":
+		return "WEEKLY"
+	case "Monthly":
+		return "MONTHLY"
+	case "Yearly":
+		return "YEARLY"
+	default:
+		return strings.ToUpper(dateType)
+	}
+}
+
+// ascRequest issues a GET against the App Store Connect API v1, retrying on
+// a rate-limited or temporarily unavailable response, or a transport-level
+// error (connection reset, timeout, ...).
+func (c Client) ascRequest(ctx context.Context, endpoint string, q url.Values) ([]byte, error) {
+	retry := c.Retry.withDefaults()
+	var lastErr error
+	for attempt := 0; attempt < retry.MaxAttempts; attempt++ {
+		token, err := c.jwt.bearerToken()
+		if err != nil {
+			return nil, err
+		}
+
+		req, err := http.NewRequestWithContext(ctx, "GET", endpoint+"?"+q.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Accept", "application/a-gzip")
+
+		res, err := c.httpCli.Do(req)
+		if err != nil {
+			lastErr = err
+			if attempt == retry.MaxAttempts-1 {
+				return nil, lastErr
+			}
+			if !sleepRetry(ctx, retry, attempt, "") {
+				return nil, lastErr
+			}
+			continue
+		}
+		body, err := ioutil.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		if res.StatusCode == http.StatusOK {
+			return body, nil
+		}
+
+		apiErr := decodeASCError(res.StatusCode, body)
+		lastErr = apiErr
+		if !retryableStatus(res.StatusCode) || attempt == retry.MaxAttempts-1 {
+			return nil, apiErr
+		}
+		if !sleepRetry(ctx, retry, attempt, res.Header.Get("Retry-After")) {
+			return nil, lastErr
+		}
+	}
+	return nil, lastErr
+}
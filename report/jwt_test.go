@@ -0,0 +1,264 @@
+package report
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newTestJWTAuth(t *testing.T) *jwtAuth {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generating test key: %v", err)
+	}
+	return &jwtAuth{
+		issuerID:     "issuer",
+		keyID:        "key",
+		vendorNumber: 1,
+		privateKey:   key,
+	}
+}
+
+// TestJWTAuthBearerTokenConcurrent exercises bearerToken from many goroutines
+// at once, as happens when batch.Downloader fans GetSalesReport/
+// GetFinanceReport calls for a JWT-mode Client out across Concurrency
+// goroutines. Run with -race to catch unsynchronized access to
+// token/tokenExpiry.
+func TestJWTAuthBearerTokenConcurrent(t *testing.T) {
+	a := newTestJWTAuth(t)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := a.bearerToken(); err != nil {
+				t.Errorf("bearerToken: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// TestParseES256PrivateKeyRejectsWrongCurve guards against a panic in
+// mintJWT/encodeES256Signature: a syntactically valid PKCS8 ECDSA key on a
+// curve other than P-256 (e.g. P-384) passes the *ecdsa.PrivateKey type
+// assertion, but its r/s don't fit the hardcoded 32-byte ES256 signature
+// width.
+func TestParseES256PrivateKeyRejectsWrongCurve(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generating P-384 test key: %v", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshaling test key: %v", err)
+	}
+	pemBytes := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+
+	if _, err := parseES256PrivateKey(pemBytes); err == nil {
+		t.Fatalf("parseES256PrivateKey accepted a P-384 key, want error")
+	}
+}
+
+func TestJWTAuthBearerTokenCaches(t *testing.T) {
+	a := newTestJWTAuth(t)
+
+	first, err := a.bearerToken()
+	if err != nil {
+		t.Fatalf("bearerToken: %v", err)
+	}
+	second, err := a.bearerToken()
+	if err != nil {
+		t.Fatalf("bearerToken: %v", err)
+	}
+	if first != second {
+		t.Fatalf("bearerToken returned a fresh token before expiry")
+	}
+
+	a.tokenExpiry = time.Now().Add(-time.Second)
+	third, err := a.bearerToken()
+	if err != nil {
+		t.Fatalf("bearerToken: %v", err)
+	}
+	if third == first {
+		t.Fatalf("bearerToken did not refresh an expired token")
+	}
+}
+
+// withTestASCEndpoints points ascSalesEndpoint/ascFinanceEndpoint at srv for
+// the lifetime of the test, restoring the real Apple URLs on cleanup.
+func withTestASCEndpoints(t *testing.T, srv *httptest.Server) {
+	t.Helper()
+	origSales, origFinance := ascSalesEndpoint, ascFinanceEndpoint
+	ascSalesEndpoint, ascFinanceEndpoint = srv.URL, srv.URL
+	t.Cleanup(func() { ascSalesEndpoint, ascFinanceEndpoint = origSales, origFinance })
+}
+
+// TestGetSalesReportRoutesThroughASC drives GetSalesReport end-to-end against
+// a fake App Store Connect API v1 server and asserts the filter params and
+// bearer token it's called with, the thing this whole request is about.
+func TestGetSalesReportRoutesThroughASC(t *testing.T) {
+	var gotURL *url.URL
+	var gotAuth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotURL = r.URL
+		gotAuth = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("sales-body"))
+	}))
+	defer srv.Close()
+	withTestASCEndpoints(t, srv)
+
+	c := Client{jwt: newTestJWTAuth(t), httpCli: newHTTPClient()}
+	body, err := c.GetSalesReport(context.Background(), 0, 1, "SUBSCRIPTION", "Summary", "Daily", "20240102")
+	if err != nil {
+		t.Fatalf("GetSalesReport: %v", err)
+	}
+	if string(body) != "sales-body" {
+		t.Errorf("body = %q, want %q", body, "sales-body")
+	}
+	if gotURL == nil {
+		t.Fatal("ASC endpoint was never called")
+	}
+
+	q := gotURL.Query()
+	wantFilters := map[string]string{
+		"filter[reportType]":    "SUBSCRIPTION",
+		"filter[reportSubType]": "SUMMARY",
+		"filter[frequency]":     "DAILY",
+		"filter[reportDate]":    "20240102",
+		"filter[vendorNumber]":  "1",
+	}
+	for k, want := range wantFilters {
+		if got := q.Get(k); got != want {
+			t.Errorf("query %s = %q, want %q", k, got, want)
+		}
+	}
+	if !strings.HasPrefix(gotAuth, "Bearer ") {
+		t.Errorf("Authorization = %q, want Bearer token", gotAuth)
+	}
+}
+
+// TestGetFinanceReportRoutesThroughASC mirrors
+// TestGetSalesReportRoutesThroughASC for GetFinanceReport's filter mapping.
+func TestGetFinanceReportRoutesThroughASC(t *testing.T) {
+	var gotURL *url.URL
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotURL = r.URL
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("finance-body"))
+	}))
+	defer srv.Close()
+	withTestASCEndpoints(t, srv)
+
+	c := Client{jwt: newTestJWTAuth(t), httpCli: newHTTPClient()}
+	body, err := c.GetFinanceReport(context.Background(), 0, 1, "US", "Financial", 2024, 3)
+	if err != nil {
+		t.Fatalf("GetFinanceReport: %v", err)
+	}
+	if string(body) != "finance-body" {
+		t.Errorf("body = %q, want %q", body, "finance-body")
+	}
+
+	q := gotURL.Query()
+	wantFilters := map[string]string{
+		"filter[regionCode]":   "US",
+		"filter[reportType]":   "FINANCIAL",
+		"filter[reportDate]":   "2024-03",
+		"filter[vendorNumber]": "1",
+	}
+	for k, want := range wantFilters {
+		if got := q.Get(k); got != want {
+			t.Errorf("query %s = %q, want %q", k, got, want)
+		}
+	}
+}
+
+// TestGetSalesReportASCVendorMismatch guards the jwtAuth.vendorNumber
+// invariant: a vendor argument that disagrees with the JWTConfig the Client
+// was built with must fail loudly rather than being silently ignored.
+func TestGetSalesReportASCVendorMismatch(t *testing.T) {
+	c := Client{jwt: newTestJWTAuth(t)}
+	_, err := c.GetSalesReport(context.Background(), 0, 999, "SUBSCRIPTION", "Summary", "Daily", "20240102")
+	if err == nil {
+		t.Fatal("GetSalesReport with a mismatched vendor number returned no error")
+	}
+}
+
+// TestAscRequestRetries503ThenSucceeds drives the ASC retry loop end-to-end
+// against a fake server that fails twice with 503 before succeeding.
+func TestAscRequestRetries503ThenSucceeds(t *testing.T) {
+	var attempts int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"errors":[{"status":"503","code":"SERVICE_UNAVAILABLE"}]}`))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	c := Client{
+		jwt:     newTestJWTAuth(t),
+		httpCli: newHTTPClient(),
+		Retry:   RetryPolicy{MaxAttempts: 4, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond},
+	}
+	body, err := c.ascRequest(context.Background(), srv.URL, url.Values{})
+	if err != nil {
+		t.Fatalf("ascRequest: %v", err)
+	}
+	if string(body) != "ok" {
+		t.Errorf("body = %q, want %q", body, "ok")
+	}
+	if attempts != 3 {
+		t.Errorf("attempts = %d, want 3", attempts)
+	}
+}
+
+// TestAscRequestGivesUpAndReturnsAPIError exhausts MaxAttempts against a
+// server that always fails, and asserts the final APIError surfaces.
+func TestAscRequestGivesUpAndReturnsAPIError(t *testing.T) {
+	var attempts int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"errors":[{"status":"503","code":"SERVICE_UNAVAILABLE"}]}`))
+	}))
+	defer srv.Close()
+
+	c := Client{
+		jwt:     newTestJWTAuth(t),
+		httpCli: newHTTPClient(),
+		Retry:   RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond},
+	}
+	_, err := c.ascRequest(context.Background(), srv.URL, url.Values{})
+	if err == nil {
+		t.Fatal("ascRequest against an always-failing server returned no error")
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("ascRequest error = %v (%T), want *APIError", err, err)
+	}
+	if apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("APIError.StatusCode = %d, want %d", apiErr.StatusCode, http.StatusServiceUnavailable)
+	}
+	if attempts != 2 {
+		t.Errorf("attempts = %d, want MaxAttempts=2", attempts)
+	}
+}
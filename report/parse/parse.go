@@ -0,0 +1,310 @@
+// Package parse decodes the gzipped, tab-separated report bodies returned by
+// report.Client into typed Go structs, so callers no longer need to parse
+// Apple's report formats by hand.
+package parse
+
+import (
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// dateLayout is the date format used throughout Sales and Finance reports.
+const dateLayout = "01/02/2006"
+
+// SalesRow is a single row of a Sales (or Newsstand) report.
+type SalesRow struct {
+	Provider                  string
+	ProviderCountry           string
+	SKU                       string
+	Developer                 string
+	Title                     string
+	Version                   string
+	ProductTypeIdentifier     string
+	Units                     int
+	DeveloperProceeds         decimal.Decimal
+	BeginDate                 time.Time
+	EndDate                   time.Time
+	CustomerCurrency          string
+	CountryCode               string
+	CurrencyOfProceeds        string
+	AppleIdentifier           string
+	Subscription              string
+	Period                    string
+	Category                  string
+	PromoCode                 string
+	ParentIdentifier          string
+	SubscriptionOfferName     string
+	SubscriptionOfferDuration string
+}
+
+// ParseSalesReport gunzips r and decodes its tab-separated body into typed
+// rows. Columns that Apple omits for a given report type/subtype are left
+// at their zero value.
+func ParseSalesReport(r io.Reader) ([]SalesRow, error) {
+	lines, header, err := readTSV(r)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]SalesRow, 0, len(lines))
+	for _, fields := range lines {
+		m := zip(header, fields)
+		row, err := parseSalesRow(m)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func parseSalesRow(m fields) (SalesRow, error) {
+	var row SalesRow
+	var err error
+
+	row.Provider = m.str("Provider")
+	row.ProviderCountry = m.str("Provider Country")
+	row.SKU = m.str("SKU")
+	row.Developer = m.str("Developer")
+	row.Title = m.str("Title")
+	row.Version = m.str("Version")
+	row.ProductTypeIdentifier = m.str("Product Type Identifier")
+	row.CustomerCurrency = m.str("Customer Currency")
+	row.CountryCode = m.str("Country Code")
+	row.CurrencyOfProceeds = m.str("Currency of Proceeds")
+	row.AppleIdentifier = m.str("Apple Identifier")
+	row.Subscription = m.str("Subscription")
+	row.Period = m.str("Period")
+	row.Category = m.str("Category")
+	row.PromoCode = m.str("Promo Code")
+	row.ParentIdentifier = m.str("Parent Identifier")
+	row.SubscriptionOfferName = m.str("Subscription Offer Name")
+	row.SubscriptionOfferDuration = m.str("Subscription Offer Duration")
+
+	if row.Units, err = m.int("Units"); err != nil {
+		return row, fmt.Errorf("parsing Units: %w", err)
+	}
+	if row.DeveloperProceeds, err = m.decimal("Developer Proceeds"); err != nil {
+		return row, fmt.Errorf("parsing Developer Proceeds: %w", err)
+	}
+	if row.BeginDate, err = m.date("Begin Date"); err != nil {
+		return row, fmt.Errorf("parsing Begin Date: %w", err)
+	}
+	if row.EndDate, err = m.date("End Date"); err != nil {
+		return row, fmt.Errorf("parsing End Date: %w", err)
+	}
+
+	return row, nil
+}
+
+// FinanceHeader describes the reporting period a Finance report covers, as
+// carried by the report's leading metadata line.
+type FinanceHeader struct {
+	VendorIdentifier string
+	FiscalYear       int
+	FiscalPeriod     int
+	RegionCode       string
+}
+
+// FinanceRow is a single SKU (or summary/totals) line of a Finance report.
+type FinanceRow struct {
+	SKU                  string
+	Title                string
+	ArtistDeveloper      string
+	VendorIdentifier     string
+	Quantity             int
+	PartnerShare         decimal.Decimal
+	ExtendedPartnerShare decimal.Decimal
+	PartnerShareCurrency string
+	SalesOrReturn        string
+	RoyaltyPrice         decimal.Decimal
+	CustomerCurrency     string
+	CountryOfSale        string
+}
+
+// FinanceReport is a fully decoded Finance report: the metadata header, one
+// row per SKU, and the trailing totals line Apple appends as Summary.
+type FinanceReport struct {
+	Header  FinanceHeader
+	Rows    []FinanceRow
+	Summary FinanceRow
+}
+
+// ParseFinanceReport gunzips r and decodes the multi-section Finance report
+// body: a metadata line (fiscal year/period/region), a column header line,
+// one line per SKU, and a trailing totals/summary line.
+func ParseFinanceReport(r io.Reader) (FinanceReport, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return FinanceReport{}, fmt.Errorf("opening gzip body: %w", err)
+	}
+	defer gz.Close()
+
+	scanner := bufio.NewScanner(gz)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+
+	if !scanner.Scan() {
+		return FinanceReport{}, errors.New("empty finance report")
+	}
+	header, err := parseFinanceHeader(scanner.Text())
+	if err != nil {
+		return FinanceReport{}, err
+	}
+
+	if !scanner.Scan() {
+		return FinanceReport{}, errors.New("finance report is missing its column header row")
+	}
+	columns := strings.Split(scanner.Text(), "\t")
+
+	var lines [][]string
+	for scanner.Scan() {
+		lines = append(lines, strings.Split(scanner.Text(), "\t"))
+	}
+	if err := scanner.Err(); err != nil {
+		return FinanceReport{}, err
+	}
+	if len(lines) == 0 {
+		return FinanceReport{}, errors.New("finance report has no rows")
+	}
+
+	rows := make([]FinanceRow, 0, len(lines)-1)
+	for _, line := range lines[:len(lines)-1] {
+		row, err := parseFinanceRow(zip(columns, line))
+		if err != nil {
+			return FinanceReport{}, err
+		}
+		rows = append(rows, row)
+	}
+	summary, err := parseFinanceRow(zip(columns, lines[len(lines)-1]))
+	if err != nil {
+		return FinanceReport{}, err
+	}
+
+	return FinanceReport{Header: header, Rows: rows, Summary: summary}, nil
+}
+
+func parseFinanceHeader(line string) (FinanceHeader, error) {
+	tokens := strings.Split(line, "\t")
+	m := make(fields, len(tokens)/2)
+	for i := 0; i+1 < len(tokens); i += 2 {
+		m[tokens[i]] = tokens[i+1]
+	}
+
+	var header FinanceHeader
+	var err error
+
+	header.VendorIdentifier = m.str("Vendor Identifier")
+	header.RegionCode = m.str("Region Code")
+	if header.FiscalYear, err = m.int("Fiscal Year"); err != nil {
+		return header, fmt.Errorf("parsing Fiscal Year: %w", err)
+	}
+	if header.FiscalPeriod, err = m.int("Fiscal Period"); err != nil {
+		return header, fmt.Errorf("parsing Fiscal Period: %w", err)
+	}
+	return header, nil
+}
+
+func parseFinanceRow(m fields) (FinanceRow, error) {
+	var row FinanceRow
+	var err error
+
+	row.SKU = m.str("SKU")
+	row.Title = m.str("Title")
+	row.ArtistDeveloper = m.str("Artist/Developer")
+	row.VendorIdentifier = m.str("Vendor Identifier")
+	row.PartnerShareCurrency = m.str("Partner Share Currency")
+	row.SalesOrReturn = m.str("Sales or Return")
+	row.CustomerCurrency = m.str("Customer Currency")
+	row.CountryOfSale = m.str("Country Of Sale")
+
+	if row.Quantity, err = m.int("Quantity"); err != nil {
+		return row, fmt.Errorf("parsing Quantity: %w", err)
+	}
+	if row.PartnerShare, err = m.decimal("Partner Share"); err != nil {
+		return row, fmt.Errorf("parsing Partner Share: %w", err)
+	}
+	if row.ExtendedPartnerShare, err = m.decimal("Extended Partner Share"); err != nil {
+		return row, fmt.Errorf("parsing Extended Partner Share: %w", err)
+	}
+	if row.RoyaltyPrice, err = m.decimal("Royalty Price"); err != nil {
+		return row, fmt.Errorf("parsing Royalty Price: %w", err)
+	}
+
+	return row, nil
+}
+
+// readTSV gunzips r and splits it into a header row and the remaining data
+// rows, tab-separated.
+func readTSV(r io.Reader) (lines [][]string, header []string, err error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("opening gzip body: %w", err)
+	}
+	defer gz.Close()
+
+	scanner := bufio.NewScanner(gz)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+
+	if !scanner.Scan() {
+		return nil, nil, errors.New("empty report")
+	}
+	header = strings.Split(scanner.Text(), "\t")
+
+	for scanner.Scan() {
+		lines = append(lines, strings.Split(scanner.Text(), "\t"))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+	return lines, header, nil
+}
+
+// fields is a single decoded row, keyed by its column name.
+type fields map[string]string
+
+func zip(keys, values []string) fields {
+	m := make(fields, len(keys))
+	for i, key := range keys {
+		if i >= len(values) {
+			break
+		}
+		m[key] = values[i]
+	}
+	return m
+}
+
+func (m fields) str(key string) string {
+	return m[key]
+}
+
+func (m fields) int(key string) (int, error) {
+	v := m[key]
+	if v == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(v)
+}
+
+func (m fields) decimal(key string) (decimal.Decimal, error) {
+	v := m[key]
+	if v == "" {
+		return decimal.Zero, nil
+	}
+	return decimal.NewFromString(v)
+}
+
+func (m fields) date(key string) (time.Time, error) {
+	v := m[key]
+	if v == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(dateLayout, v)
+}
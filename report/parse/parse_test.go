@@ -0,0 +1,103 @@
+package parse
+
+import (
+	"bytes"
+	"compress/gzip"
+	"strings"
+	"testing"
+)
+
+func gzipString(t *testing.T, s string) *bytes.Reader {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write([]byte(s)); err != nil {
+		t.Fatalf("gzipping test fixture: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("closing gzip writer: %v", err)
+	}
+	return bytes.NewReader(buf.Bytes())
+}
+
+func TestParseSalesReport(t *testing.T) {
+	header := []string{
+		"Provider", "Provider Country", "SKU", "Developer", "Title", "Version",
+		"Product Type Identifier", "Units", "Developer Proceeds", "Begin Date",
+		"End Date", "Customer Currency", "Country Code", "Currency of Proceeds",
+		"Apple Identifier", "Subscription", "Period", "Category", "Promo Code",
+		"Parent Identifier", "Subscription Offer Name", "Subscription Offer Duration",
+	}
+	row := []string{
+		"Apple", "US", "com.example.app", "Example Inc", "Example App", "1.2.3",
+		"1F", "3", "1.99", "07/01/2024", "07/01/2024", "USD", "US", "USD",
+		"123456789", "", "", "Games", "", "", "", "",
+	}
+	body := strings.Join(header, "\t") + "\n" + strings.Join(row, "\t") + "\n"
+
+	rows, err := ParseSalesReport(gzipString(t, body))
+	if err != nil {
+		t.Fatalf("ParseSalesReport: %v", err)
+	}
+	if len(rows) != 1 {
+		t.Fatalf("ParseSalesReport returned %d rows, want 1", len(rows))
+	}
+
+	got := rows[0]
+	if got.SKU != "com.example.app" {
+		t.Errorf("SKU = %q, want %q", got.SKU, "com.example.app")
+	}
+	if got.Units != 3 {
+		t.Errorf("Units = %d, want 3", got.Units)
+	}
+	if got.DeveloperProceeds.String() != "1.99" {
+		t.Errorf("DeveloperProceeds = %s, want 1.99", got.DeveloperProceeds)
+	}
+	if got.BeginDate.Format("01/02/2006") != "07/01/2024" {
+		t.Errorf("BeginDate = %v, want 07/01/2024", got.BeginDate)
+	}
+}
+
+func TestParseFinanceReport(t *testing.T) {
+	metaLine := "Vendor Identifier\t12345\tRegion Code\tWW\tFiscal Year\t2024\tFiscal Period\t3"
+	columns := []string{
+		"SKU", "Title", "Artist/Developer", "Vendor Identifier", "Quantity",
+		"Partner Share", "Extended Partner Share", "Partner Share Currency",
+		"Sales or Return", "Royalty Price", "Customer Currency", "Country Of Sale",
+	}
+	row := []string{"com.example.app", "Example App", "Example Inc", "12345", "2", "0.70", "1.40", "USD", "S", "0.99", "USD", "US"}
+	summary := []string{"", "", "", "", "2", "0.70", "1.40", "USD", "", "", "", ""}
+
+	body := metaLine + "\n" + strings.Join(columns, "\t") + "\n" + strings.Join(row, "\t") + "\n" + strings.Join(summary, "\t") + "\n"
+
+	report, err := ParseFinanceReport(gzipString(t, body))
+	if err != nil {
+		t.Fatalf("ParseFinanceReport: %v", err)
+	}
+
+	if report.Header.FiscalYear != 2024 || report.Header.FiscalPeriod != 3 {
+		t.Errorf("Header = %+v, want FiscalYear 2024 FiscalPeriod 3", report.Header)
+	}
+	if report.Header.RegionCode != "WW" {
+		t.Errorf("RegionCode = %q, want %q", report.Header.RegionCode, "WW")
+	}
+	if len(report.Rows) != 1 {
+		t.Fatalf("ParseFinanceReport returned %d rows, want 1", len(report.Rows))
+	}
+	if report.Rows[0].SKU != "com.example.app" {
+		t.Errorf("Rows[0].SKU = %q, want %q", report.Rows[0].SKU, "com.example.app")
+	}
+	if report.Summary.Quantity != 2 {
+		t.Errorf("Summary.Quantity = %d, want 2", report.Summary.Quantity)
+	}
+}
+
+func TestParseFinanceReportMissingRows(t *testing.T) {
+	metaLine := "Vendor Identifier\t12345\tRegion Code\tWW\tFiscal Year\t2024\tFiscal Period\t3"
+	columns := "SKU\tTitle"
+	body := metaLine + "\n" + columns + "\n"
+
+	if _, err := ParseFinanceReport(gzipString(t, body)); err == nil {
+		t.Fatal("ParseFinanceReport with no rows: got nil error, want an error")
+	}
+}
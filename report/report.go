@@ -23,12 +23,23 @@ type Client struct {
 	AccessToken string
 	Mode        string
 	httpCli     *http.Client
+
+	// jwt is set when the Client was built with NewJWTClient and routes
+	// Get*Report calls through the App Store Connect API v1 instead of the
+	// legacy Reporter endpoints.
+	jwt *jwtAuth
+
+	// Retry controls how the Client retries a request that came back
+	// rate-limited or temporarily unavailable. The zero value uses sane
+	// defaults.
+	Retry RetryPolicy
 }
 
 // Config base properties
 type Config struct {
 	AccessToken string
 	Mode        string
+	Retry       RetryPolicy
 }
 
 // Request to Reporter endpoints
@@ -56,21 +67,26 @@ func NewClient(cfg Config) (*Client, error) {
 	return &Client{
 		AccessToken: cfg.AccessToken,
 		Mode:        cfg.Mode,
-		httpCli: &http.Client{
-			Transport: &http.Transport{
-				Dial: (&net.Dialer{
-					Timeout:   10 * time.Second,
-					KeepAlive: 180 * time.Second,
-				}).Dial,
-				TLSHandshakeTimeout:   10 * time.Second,
-				ResponseHeaderTimeout: 300 * time.Second,
-				DisableCompression:    true,
-				DisableKeepAlives:     false,
-			},
-		},
+		httpCli:     newHTTPClient(),
+		Retry:       cfg.Retry,
 	}, nil
 }
 
+func newHTTPClient() *http.Client {
+	return &http.Client{
+		Transport: &http.Transport{
+			Dial: (&net.Dialer{
+				Timeout:   10 * time.Second,
+				KeepAlive: 180 * time.Second,
+			}).Dial,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ResponseHeaderTimeout: 300 * time.Second,
+			DisableCompression:    true,
+			DisableKeepAlives:     false,
+		},
+	}
+}
+
 func (c Client) CloseIdleConnections() {
 	c.httpCli.CloseIdleConnections()
 }
@@ -124,12 +140,21 @@ func (c Client) GetFinanceVendorsAndRegions(ctx context.Context, account int) ([
 	return c.send(ctx, financeEndpoint, req)
 }
 
-// GetSalesReport return Sales.getReport response (is report file or error)
+// GetSalesReport return Sales.getReport response (is report file or error).
+// When the Client was built with NewJWTClient, the report is instead fetched
+// from the App Store Connect API v1 (account is ignored in that mode, and
+// vendor must match the JWTConfig's VendorNumber).
 func (c Client) GetSalesReport(ctx context.Context, account, vendor int, reportType, reportSubType, dateType, date string) ([]byte, error) {
-	err := validateSalesReportArgs(account, vendor, reportType, reportSubType, dateType, date)
+	err := validateSalesReportArgs(account, vendor, reportType, reportSubType, dateType, date, c.jwt != nil)
 	if err != nil {
 		return nil, err
 	}
+	if c.jwt != nil {
+		if vendor != c.jwt.vendorNumber {
+			return nil, fmt.Errorf("vendor %d does not match the JWTConfig vendor number %d", vendor, c.jwt.vendorNumber)
+		}
+		return c.ascSalesReport(ctx, vendor, reportType, reportSubType, dateType, date)
+	}
 	req := c.getBaseRequest()
 	req.SetAccount(account)
 	qI := "%%5Bp%%3DReporter.properties%%2C+m%%3D%s%%2C+Sales.getReport%%2C+%d%%2C%s%%2C%s%%2C%s%%2C%s%%5D"
@@ -137,12 +162,21 @@ func (c Client) GetSalesReport(ctx context.Context, account, vendor int, reportT
 	return c.send(ctx, salesEndpoint, req)
 }
 
-// GetFinanceReport return Finance.getReport response (is report file or error)
+// GetFinanceReport return Finance.getReport response (is report file or
+// error). When the Client was built with NewJWTClient, the report is instead
+// fetched from the App Store Connect API v1 (account is ignored in that mode,
+// and vendor must match the JWTConfig's VendorNumber).
 func (c Client) GetFinanceReport(ctx context.Context, account, vendor int, regionCode, reportType string, fiscalYear, fiscalPeriod int) ([]byte, error) {
-	err := validateFinancialReportArgs(account, vendor, regionCode, reportType, fiscalYear, fiscalPeriod)
+	err := validateFinancialReportArgs(account, vendor, regionCode, reportType, fiscalYear, fiscalPeriod, c.jwt != nil)
 	if err != nil {
 		return nil, err
 	}
+	if c.jwt != nil {
+		if vendor != c.jwt.vendorNumber {
+			return nil, fmt.Errorf("vendor %d does not match the JWTConfig vendor number %d", vendor, c.jwt.vendorNumber)
+		}
+		return c.ascFinanceReport(ctx, vendor, regionCode, reportType, fiscalYear, fiscalPeriod)
+	}
 	req := c.getBaseRequest()
 	req.SetAccount(account)
 	qI := "%%5Bp%%3DReporter.properties%%2C+m%%3D%s%%2C+Finance.getReport%%2C+%d%%2C%s%%2C%s%%2C%d%%2C%d%%5D"
@@ -155,32 +189,53 @@ func (c Client) send(ctx context.Context, endpoint string, r Request) ([]byte, e
 	if err != nil {
 		return nil, err
 	}
-
 	query := fmt.Sprintf("jsonRequest=%s", string(q))
-	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, strings.NewReader(query))
-	if err != nil {
-		return nil, err
-	}
 
-	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
-	req.Header.Add("Accept", "text/html, image/gif, image/jpeg, *; q=.2, */*; q=.2")
-	req.Header.Add("User-Agent", "Java/1.8.0_112")
-	req.Header.Add("Connection", "keep-alive")
+	retry := c.Retry.withDefaults()
+	var lastErr error
+	for attempt := 0; attempt < retry.MaxAttempts; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, "POST", endpoint, strings.NewReader(query))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Add("Accept", "text/html, image/gif, image/jpeg, *; q=.2, */*; q=.2")
+		req.Header.Add("User-Agent", "Java/1.8.0_112")
+		req.Header.Add("Connection", "keep-alive")
 
-	res, err := c.httpCli.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
+		res, err := c.httpCli.Do(req)
+		if err != nil {
+			// A transport-level failure (connection reset, timeout, ...) is
+			// just as transient as a 503, so it gets the same retry
+			// treatment rather than failing the request outright.
+			lastErr = err
+			if attempt == retry.MaxAttempts-1 {
+				return nil, lastErr
+			}
+			if !sleepRetry(ctx, retry, attempt, "") {
+				return nil, lastErr
+			}
+			continue
+		}
+		body, err := ioutil.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		if res.StatusCode == http.StatusOK {
+			return body, nil
+		}
 
-	body, err := ioutil.ReadAll(res.Body)
-	if err != nil {
-		return nil, err
-	}
-	if res.StatusCode != http.StatusOK {
-		return nil, errors.New(string(body))
+		apiErr := decodeReporterError(res.StatusCode, body, c.Mode)
+		lastErr = apiErr
+		if !retryableStatus(res.StatusCode) || attempt == retry.MaxAttempts-1 {
+			return nil, apiErr
+		}
+		if !sleepRetry(ctx, retry, attempt, res.Header.Get("Retry-After")) {
+			return nil, lastErr
+		}
 	}
-	return body, nil
+	return nil, lastErr
 }
 
 func (c Client) getBaseRequest() Request {
@@ -203,15 +258,18 @@ func (cfg Config) Check() error {
 	return nil
 }
 
-func validateSalesReportArgs(account, vendor int, reportType, reportSubType, dateType, date string) error {
-	if account <= 0 {
+func validateSalesReportArgs(account, vendor int, reportType, reportSubType, dateType, date string, asc bool) error {
+	if !asc && account <= 0 {
 		return errors.New("wrong account number")
 	}
 	if vendor <= 0 {
 		return errors.New("wrong vendor number")
 	}
 
-	if reportType != "Sales" && reportType != "Newsstand" {
+	switch {
+	case reportType == "Sales" || reportType == "Newsstand":
+	case asc && isASCSalesReportType(reportType):
+	default:
 		return errors.New("wrong ReportType, use: Sales or Newsstand")
 	}
 
@@ -247,8 +305,8 @@ func validateSalesReportArgs(account, vendor int, reportType, reportSubType, dat
 	return nil
 }
 
-func validateFinancialReportArgs(account, vendor int, regionCode, reportType string, fiscalYear, fiscalPeriod int) error {
-	if account <= 0 {
+func validateFinancialReportArgs(account, vendor int, regionCode, reportType string, fiscalYear, fiscalPeriod int, asc bool) error {
+	if !asc && account <= 0 {
 		return errors.New("wrong account number")
 	}
 	if vendor <= 0 {
@@ -0,0 +1,161 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestValidateSalesReportArgsASC checks the asc branch added for the App
+// Store Connect API v1 backend: it accepts the report types only that API
+// exposes and waives the legacy account-number check.
+func TestValidateSalesReportArgsASC(t *testing.T) {
+	tests := []struct {
+		name       string
+		reportType string
+		wantErr    bool
+	}{
+		{"legacy sales still accepted", "Sales", false},
+		{"legacy newsstand still accepted", "Newsstand", false},
+		{"subscription accepted", "SUBSCRIPTION", false},
+		{"subscriber accepted", "SUBSCRIBER", false},
+		{"subscription event accepted", "SUBSCRIPTION_EVENT", false},
+		{"pre order accepted", "PRE_ORDER", false},
+		{"newsstand caps accepted", "NEWSSTAND", false},
+		{"unknown type rejected", "BOGUS", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSalesReportArgs(0, 1, tt.reportType, "Summary", "Daily", "20240102", true)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateSalesReportArgs(asc=true, %q) error = %v, wantErr %v", tt.reportType, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestValidateSalesReportArgsNonASCRejectsNewTypes guards against the ASC-only
+// report types leaking into the legacy Reporter path, which doesn't support
+// them.
+func TestValidateSalesReportArgsNonASCRejectsNewTypes(t *testing.T) {
+	err := validateSalesReportArgs(1, 1, "SUBSCRIPTION", "Summary", "Daily", "20240102", false)
+	if err == nil {
+		t.Fatal("validateSalesReportArgs(asc=false, \"SUBSCRIPTION\") returned no error")
+	}
+}
+
+// TestValidateSalesReportArgsNonASCStillRequiresAccount guards against the
+// asc account-check bypass leaking into the legacy Reporter path.
+func TestValidateSalesReportArgsNonASCStillRequiresAccount(t *testing.T) {
+	err := validateSalesReportArgs(0, 1, "Sales", "Summary", "Daily", "20240102", false)
+	if err == nil {
+		t.Fatal("validateSalesReportArgs(asc=false, account=0) returned no error")
+	}
+}
+
+// TestSendRetries503ThenSucceeds drives the legacy Reporter retry loop
+// end-to-end against a fake server that fails twice with 503 before
+// succeeding.
+func TestSendRetries503ThenSucceeds(t *testing.T) {
+	var attempts int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`<Error><Code>503</Code><Message>Service unavailable.</Message></Error>`))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	c := Client{
+		Mode:    "Robot.xml",
+		httpCli: newHTTPClient(),
+		Retry:   RetryPolicy{MaxAttempts: 4, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond},
+	}
+	body, err := c.send(context.Background(), srv.URL, c.getBaseRequest())
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if string(body) != "ok" {
+		t.Errorf("body = %q, want %q", body, "ok")
+	}
+	if attempts != 3 {
+		t.Errorf("attempts = %d, want 3", attempts)
+	}
+}
+
+// TestSendHonorsRetryAfter checks that a 429 with a Retry-After header is
+// retried after (at most) that delay rather than the jittered backoff.
+func TestSendHonorsRetryAfter(t *testing.T) {
+	var attempts int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 2 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte("slow down"))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	c := Client{
+		Mode:    "Normal",
+		httpCli: newHTTPClient(),
+		Retry:   RetryPolicy{MaxAttempts: 3, BaseDelay: time.Hour, MaxDelay: time.Hour},
+	}
+	start := time.Now()
+	body, err := c.send(context.Background(), srv.URL, c.getBaseRequest())
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if string(body) != "ok" {
+		t.Errorf("body = %q, want %q", body, "ok")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("send with Retry-After: 0 took %v, want near-instant", elapsed)
+	}
+}
+
+// TestSendGivesUpAndReturnsAPIError exhausts MaxAttempts against a server
+// that always fails, and asserts the final APIError surfaces.
+func TestSendGivesUpAndReturnsAPIError(t *testing.T) {
+	var attempts int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`<Error><Code>503</Code><Message>Service unavailable.</Message></Error>`))
+	}))
+	defer srv.Close()
+
+	c := Client{
+		Mode:    "Robot.xml",
+		httpCli: newHTTPClient(),
+		Retry:   RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond},
+	}
+	_, err := c.send(context.Background(), srv.URL, c.getBaseRequest())
+	if err == nil {
+		t.Fatal("send against an always-failing server returned no error")
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("send error = %v (%T), want *APIError", err, err)
+	}
+	if apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("APIError.StatusCode = %d, want %d", apiErr.StatusCode, http.StatusServiceUnavailable)
+	}
+	if apiErr.Code != "503" {
+		t.Errorf("APIError.Code = %q, want %q", apiErr.Code, "503")
+	}
+	if attempts != 2 {
+		t.Errorf("attempts = %d, want MaxAttempts=2", attempts)
+	}
+}